@@ -0,0 +1,129 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awscloudwatchlogsexporter"
+
+import (
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+)
+
+// PutLogEvents API limits, see
+// https://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_PutLogEvents.html
+const (
+	maxBatchSizeBytes  = 1048576
+	maxBatchEventCount = 10000
+	// perEventOverheadBytes is added by CloudWatch Logs to every event's
+	// size to account for the timestamp and other metadata.
+	perEventOverheadBytes = 26
+	maxTimestampSpan      = 24 * time.Hour
+	maxPastAge            = 14 * 24 * time.Hour
+	maxFutureSkew         = 2 * time.Hour
+)
+
+const (
+	dropReasonTooOld = "too_old"
+	dropReasonTooNew = "too_new"
+)
+
+// batcher bin-packs log events into PutLogEvents-sized batches, dropping
+// events CloudWatch Logs would reject outright.
+type batcher struct {
+	now func() time.Time
+}
+
+func newBatcher() *batcher {
+	return &batcher{now: time.Now}
+}
+
+// batch sorts events chronologically, drops events outside the window
+// CloudWatch Logs accepts, and splits the remainder into batches that each
+// respect the size, count, and 24-hour timestamp span limits. dropped counts
+// dropped events by reason, for the awscwlogs_dropped_events_total metric.
+func (b *batcher) batch(events []*cloudwatchlogs.InputLogEvent) (batches [][]*cloudwatchlogs.InputLogEvent, dropped map[string]int) {
+	dropped = make(map[string]int)
+	if len(events) == 0 {
+		return nil, dropped
+	}
+
+	sorted := make([]*cloudwatchlogs.InputLogEvent, len(events))
+	copy(sorted, events)
+	sort.Slice(sorted, func(i, j int) bool {
+		return aws.Int64Value(sorted[i].Timestamp) < aws.Int64Value(sorted[j].Timestamp)
+	})
+
+	minTimestamp := b.now().Add(-maxPastAge).UnixMilli()
+	maxTimestampMs := b.now().Add(maxFutureSkew).UnixMilli()
+
+	var accepted []*cloudwatchlogs.InputLogEvent
+	for _, event := range sorted {
+		ts := aws.Int64Value(event.Timestamp)
+		switch {
+		case ts < minTimestamp:
+			dropped[dropReasonTooOld]++
+		case ts > maxTimestampMs:
+			dropped[dropReasonTooNew]++
+		default:
+			accepted = append(accepted, event)
+		}
+	}
+
+	var current []*cloudwatchlogs.InputLogEvent
+	var currentSizeBytes int
+	var currentFirstTimestamp int64
+
+	flush := func() {
+		if len(current) > 0 {
+			batches = append(batches, current)
+			current = nil
+			currentSizeBytes = 0
+		}
+	}
+
+	for _, event := range accepted {
+		size := eventSizeBytes(event)
+		ts := aws.Int64Value(event.Timestamp)
+
+		exceedsCount := len(current) >= maxBatchEventCount
+		exceedsSize := currentSizeBytes+size > maxBatchSizeBytes
+		exceedsSpan := len(current) > 0 && time.Duration(ts-currentFirstTimestamp)*time.Millisecond > maxTimestampSpan
+
+		if exceedsCount || exceedsSize || exceedsSpan {
+			flush()
+		}
+		if len(current) == 0 {
+			currentFirstTimestamp = ts
+		}
+		current = append(current, event)
+		currentSizeBytes += size
+	}
+	flush()
+
+	return batches, dropped
+}
+
+func eventSizeBytes(event *cloudwatchlogs.InputLogEvent) int {
+	return len(aws.StringValue(event.Message)) + perEventOverheadBytes
+}
+
+func batchSizeBytes(batch []*cloudwatchlogs.InputLogEvent) int {
+	total := 0
+	for _, event := range batch {
+		total += eventSizeBytes(event)
+	}
+	return total
+}
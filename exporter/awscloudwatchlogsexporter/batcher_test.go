@@ -0,0 +1,125 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixedNow(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+func eventAt(t time.Time, message string) *cloudwatchlogs.InputLogEvent {
+	return &cloudwatchlogs.InputLogEvent{
+		Timestamp: aws.Int64(t.UnixMilli()),
+		Message:   aws.String(message),
+	}
+}
+
+func TestBatcher_SplitsOnEventCount(t *testing.T) {
+	b := &batcher{now: fixedNow(time.Now())}
+	events := make([]*cloudwatchlogs.InputLogEvent, maxBatchEventCount+1)
+	for i := range events {
+		events[i] = eventAt(b.now(), "e")
+	}
+
+	batches, dropped := b.batch(events)
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0], maxBatchEventCount)
+	assert.Len(t, batches[1], 1)
+	assert.Empty(t, dropped)
+}
+
+func TestBatcher_SplitsOnSizeBytes(t *testing.T) {
+	b := &batcher{now: fixedNow(time.Now())}
+	// Sized so two events fit within maxBatchSizeBytes but three don't.
+	big := strings.Repeat("a", 400000-perEventOverheadBytes)
+	events := []*cloudwatchlogs.InputLogEvent{
+		eventAt(b.now(), big),
+		eventAt(b.now(), big),
+		eventAt(b.now(), big),
+	}
+
+	batches, dropped := b.batch(events)
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0], 2)
+	assert.Len(t, batches[1], 1)
+	assert.Empty(t, dropped)
+}
+
+func TestBatcher_SplitsOnTimestampSpan(t *testing.T) {
+	now := time.Now()
+	b := &batcher{now: fixedNow(now)}
+	events := []*cloudwatchlogs.InputLogEvent{
+		eventAt(now.Add(-25*time.Hour), "e1"),
+		eventAt(now.Add(-24*time.Hour), "e2"), // within 24h of e1
+		eventAt(now, "e3"),                    // more than 24h after e1
+	}
+
+	batches, dropped := b.batch(events)
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0], 2)
+	assert.Len(t, batches[1], 1)
+	assert.Empty(t, dropped)
+}
+
+func TestBatcher_DropsTooOldAndTooNewEvents(t *testing.T) {
+	now := time.Now()
+	b := &batcher{now: fixedNow(now)}
+	events := []*cloudwatchlogs.InputLogEvent{
+		eventAt(now.Add(-15*24*time.Hour), "too old"),
+		eventAt(now.Add(3*time.Hour), "too new"),
+		eventAt(now, "fine"),
+	}
+
+	batches, dropped := b.batch(events)
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 1)
+	assert.Equal(t, "fine", aws.StringValue(batches[0][0].Message))
+	assert.Equal(t, 1, dropped[dropReasonTooOld])
+	assert.Equal(t, 1, dropped[dropReasonTooNew])
+}
+
+func TestBatcher_EmptyInput(t *testing.T) {
+	b := newBatcher()
+	batches, dropped := b.batch(nil)
+	assert.Nil(t, batches)
+	assert.Empty(t, dropped)
+}
+
+func TestBatcher_SortsByTimestamp(t *testing.T) {
+	now := time.Now()
+	b := &batcher{now: fixedNow(now)}
+	events := []*cloudwatchlogs.InputLogEvent{
+		eventAt(now, "third"),
+		eventAt(now.Add(-2*time.Minute), "first"),
+		eventAt(now.Add(-time.Minute), "second"),
+	}
+
+	batches, _ := b.batch(events)
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 3)
+	assert.Equal(t, "first", aws.StringValue(batches[0][0].Message))
+	assert.Equal(t, "second", aws.StringValue(batches[0][1].Message))
+	assert.Equal(t, "third", aws.StringValue(batches[0][2].Message))
+}
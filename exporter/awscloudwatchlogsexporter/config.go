@@ -16,7 +16,10 @@ package awscloudwatchlogsexporter // import "github.com/open-telemetry/opentelem
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
@@ -25,18 +28,36 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/awsutil"
 )
 
+// roleARNPattern matches IAM role ARNs, e.g. arn:aws:iam::123456789012:role/my-role
+// or arn:aws-us-gov:iam::123456789012:role/my-role.
+var roleARNPattern = regexp.MustCompile(`^arn:aws[a-zA-Z-]*:iam::\d{12}:role/.+$`)
+
+// maxEMFDimensions is the maximum number of dimensions CloudWatch accepts in
+// a single EMF dimension set.
+const maxEMFDimensions = 30
+
 // Config represent a configuration for the CloudWatch logs exporter.
 type Config struct {
 	exporterhelper.RetrySettings `mapstructure:"retry_on_failure"`
 
 	// LogGroupName is the name of CloudWatch log group which defines group of log streams
-	// that share the same retention, monitoring, and access control settings.
+	// that share the same retention, monitoring, and access control settings. It may contain
+	// Go template placeholders resolved per log record against its resource attributes and
+	// attributes, e.g. "/aws/lambda/{{ index .Resource \"faas.name\" }}", so that a single
+	// exporter instance can route logs across many log groups.
 	LogGroupName string `mapstructure:"log_group_name"`
 
 	// LogStreamName is the name of CloudWatch log stream which is a sequence of log events
-	// that share the same source.
+	// that share the same source. Like LogGroupName, it supports the same template
+	// placeholders, e.g. "{{ .Attributes.host_name }}".
 	LogStreamName string `mapstructure:"log_stream_name"`
 
+	// MaxCacheSize is the maximum number of distinct (log group, log stream) pairs for which
+	// the exporter keeps pusher state - sequence tokens and whether the group/stream have
+	// already been created - in memory at once. Least-recently-used pairs are evicted once
+	// the cache is full. Defaults to 1000.
+	MaxCacheSize int `mapstructure:"max_cache_size"`
+
 	// Endpoint is the CloudWatch Logs service endpoint which the requests
 	// are forwarded to. https://docs.aws.amazon.com/general/latest/gr/cwl_region.html
 	// e.g. logs.us-east-1.amazonaws.com
@@ -63,11 +84,129 @@ type Config struct {
 	// Export raw log string instead of log wrapper
 	// Required for emf logs
 	RawLog bool `mapstructure:"raw_log,omitempty"`
+
+	// AssumeRole configures the exporter to assume an IAM role via AWS STS
+	// before creating the CloudWatch Logs client. This allows a single
+	// collector to fan logs out to log groups that live in different AWS
+	// accounts by instantiating one exporter instance per account, each with
+	// its own AssumeRole settings.
+	AssumeRole AssumeRole `mapstructure:"assume_role"`
+
+	// EMF configures emitting CloudWatch Embedded Metric Format documents,
+	// built from numeric attributes, instead of plain log lines. It is used
+	// both by the logs pipeline (when numeric attributes are present on a
+	// log record) and by the metrics pipeline created via
+	// createMetricsExporter.
+	EMF EMFConfig `mapstructure:"emf"`
+
+	// Mapping configures how a log record is turned into a CloudWatch Logs
+	// event: where its timestamp comes from, which attributes are kept, and
+	// how the body is serialized. When RawLog is set, only TimestampSource
+	// applies and the body is forwarded as is. When EMF.Enabled is set,
+	// records with a numeric MetricNameKeys value become EMF documents and
+	// ignore Mapping; all other records are mapped as usual.
+	Mapping Mapping `mapstructure:"mapping"`
+}
+
+// allowed values for Mapping.TimestampSource, beyond an attribute path.
+const (
+	timestampSourceLogTimestamp      = "log.timestamp"
+	timestampSourceObservedTimestamp = "observed_timestamp"
+	attributeTimestampSourcePrefix   = "attribute:"
+)
+
+// allowed values for Mapping.Format.
+const (
+	mappingFormatJSON   = "json"
+	mappingFormatLogfmt = "logfmt"
+)
+
+// Mapping controls how a log record's timestamp, attributes, and body are
+// mapped onto a CloudWatch Logs event.
+type Mapping struct {
+	// TimestampSource selects the CloudWatch event timestamp: "log.timestamp"
+	// (default) uses the log record's own timestamp, "observed_timestamp"
+	// uses the time the collector observed it, and "attribute:<key>" reads an
+	// attribute (falling back to the log record's timestamp if unset).
+	TimestampSource string `mapstructure:"timestamp_source"`
+
+	// Format controls how the event body is serialized: "json" (default) or
+	// "logfmt"/key=value.
+	Format string `mapstructure:"format"`
+
+	// PromoteAttributes lists attribute keys to surface as top-level JSON
+	// fields rather than nested under "attributes". Ignored when Format is
+	// "logfmt", where all kept attributes are already top-level key=value
+	// pairs.
+	PromoteAttributes []string `mapstructure:"promote_attributes"`
+
+	// DropAttributes lists attribute keys to omit before serialization.
+	// Mutually exclusive with KeepAttributes.
+	DropAttributes []string `mapstructure:"drop_attributes"`
+
+	// KeepAttributes, if non-empty, is the exhaustive allow-list of
+	// attribute keys to retain before serialization. Mutually exclusive with
+	// DropAttributes.
+	KeepAttributes []string `mapstructure:"keep_attributes"`
+}
+
+// EMFConfig controls CloudWatch Embedded Metric Format emission.
+type EMFConfig struct {
+	// Enabled turns on EMF document emission.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Namespace is the CloudWatch metrics namespace metrics are published
+	// under, e.g. "MyApp".
+	Namespace string `mapstructure:"namespace"`
+
+	// Dimensions lists the dimension sets to publish for each metric. Each
+	// inner slice is one set of attribute/resource attribute keys whose
+	// values become CloudWatch dimensions, e.g. [["service.name"],
+	// ["service.name","host.name"]]. CloudWatch allows at most 30 dimensions
+	// per set.
+	Dimensions [][]string `mapstructure:"dimensions"`
+
+	// MetricNameKeys lists the attribute keys, on logs with numeric
+	// attributes, that should be promoted to CloudWatch metrics. Ignored by
+	// the metrics pipeline, where the OTLP metric name is used directly.
+	MetricNameKeys []string `mapstructure:"metric_name_keys"`
+}
+
+// AssumeRole defines the settings used to assume an IAM role prior to
+// creating the CloudWatch Logs client.
+type AssumeRole struct {
+	// ARN is the Amazon Resource Name of the role to assume. If empty, no
+	// role is assumed and the exporter uses the default credential chain.
+	ARN string `mapstructure:"arn"`
+
+	// ExternalID is passed to sts:AssumeRole. It is typically required when
+	// the role being assumed belongs to a third-party account.
+	ExternalID string `mapstructure:"external_id"`
+
+	// SessionName identifies the assumed role session, e.g. in AWS CloudTrail.
+	SessionName string `mapstructure:"session_name"`
+
+	// SessionDuration is how long the assumed role credentials are valid for
+	// before the AWS SDK automatically refreshes them. Defaults to 15 minutes,
+	// the AWS SDK default, when unset.
+	SessionDuration time.Duration `mapstructure:"session_duration"`
 }
 
 type QueueSettings struct {
 	// QueueSize set the length of the sending queue
 	QueueSize int `mapstructure:"queue_size"`
+
+	// Persistent enables a write-ahead queue backed by the storage extension
+	// referenced by StorageID, so buffered log events survive a collector
+	// restart instead of being held only in memory. The single-in-flight
+	// invariant required by CloudWatch sequence tokens still applies; only
+	// the durability of the queue changes.
+	Persistent bool `mapstructure:"persistent"`
+
+	// StorageID is the component ID of the extension/storage extension (e.g.
+	// file_storage) used to persist the queue. Required when Persistent is
+	// true.
+	StorageID component.ID `mapstructure:"storage"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -83,6 +222,9 @@ func (config *Config) Validate() error {
 	if config.QueueSettings.QueueSize < 1 {
 		return errors.New("'sending_queue.queue_size' must be 1 or greater")
 	}
+	if config.QueueSettings.Persistent && config.QueueSettings.StorageID == (component.ID{}) {
+		return errors.New("'sending_queue.storage' must be set when 'sending_queue.persistent' is true")
+	}
 	if !isValidRetentionValue(config.LogRetention) {
 		return errors.New("invalid value for retention policy.  Please make sure to use the following values: 0 (Never Expire), 1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1827, 2192, 2557, 2922, 3288, or 3653")
 	}
@@ -90,6 +232,45 @@ func (config *Config) Validate() error {
 	if tagInputReturnVal != "Valid" {
 		return errors.New(tagInputReturnVal)
 	}
+	if config.AssumeRole.ARN != "" && !roleARNPattern.MatchString(config.AssumeRole.ARN) {
+		return errors.New("'assume_role.arn' is not a valid IAM role ARN")
+	}
+	if config.MaxCacheSize < 1 {
+		return errors.New("'max_cache_size' must be 1 or greater")
+	}
+	if config.EMF.Enabled {
+		if config.EMF.Namespace == "" {
+			return errors.New("'emf.namespace' must be set when 'emf.enabled' is true")
+		}
+		for _, dimensionSet := range config.EMF.Dimensions {
+			if len(dimensionSet) > maxEMFDimensions {
+				return fmt.Errorf("'emf.dimensions' sets must not exceed %d dimensions", maxEMFDimensions)
+			}
+		}
+	}
+	if err := config.Mapping.validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Mapping) validate() error {
+	switch m.TimestampSource {
+	case "", timestampSourceLogTimestamp, timestampSourceObservedTimestamp:
+	default:
+		if !strings.HasPrefix(m.TimestampSource, attributeTimestampSourcePrefix) {
+			return fmt.Errorf("'mapping.timestamp_source' must be one of %q, %q, or %q<key>, got %q",
+				timestampSourceLogTimestamp, timestampSourceObservedTimestamp, attributeTimestampSourcePrefix, m.TimestampSource)
+		}
+	}
+	switch m.Format {
+	case "", mappingFormatJSON, mappingFormatLogfmt:
+	default:
+		return fmt.Errorf("'mapping.format' must be one of %q or %q, got %q", mappingFormatJSON, mappingFormatLogfmt, m.Format)
+	}
+	if len(m.DropAttributes) > 0 && len(m.KeepAttributes) > 0 {
+		return errors.New("'mapping.drop_attributes' and 'mapping.keep_attributes' are mutually exclusive")
+	}
 	return nil
 }
 
@@ -150,12 +331,16 @@ func isValidTagsInput(input map[string]*string) string {
 }
 
 func (config *Config) enforcedQueueSettings() exporterhelper.QueueSettings {
-	return exporterhelper.QueueSettings{
+	qs := exporterhelper.QueueSettings{
 		Enabled: true,
-		// due to the sequence token, there can be only one request in flight
+		// due to the sequence token, there can be only one request in flight,
+		// persistent or not
 		NumConsumers: 1,
 		QueueSize:    config.QueueSettings.QueueSize,
 	}
+	if config.QueueSettings.Persistent {
+		storageID := config.QueueSettings.StorageID
+		qs.StorageID = &storageID
+	}
+	return qs
 }
-
-// TODO(jbd): Add ARN role to config.
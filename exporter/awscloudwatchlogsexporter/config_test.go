@@ -0,0 +1,100 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+)
+
+func TestConfigValidate_AssumeRoleARN(t *testing.T) {
+	tests := []struct {
+		name    string
+		arn     string
+		wantErr bool
+	}{
+		{name: "empty is allowed (no role assumed)", arn: ""},
+		{name: "valid commercial partition", arn: "arn:aws:iam::123456789012:role/my-role"},
+		{name: "valid gov partition", arn: "arn:aws-us-gov:iam::123456789012:role/my-role"},
+		{name: "missing account id", arn: "arn:aws:iam::role/my-role", wantErr: true},
+		{name: "wrong service", arn: "arn:aws:s3::123456789012:role/my-role", wantErr: true},
+		{name: "not an arn at all", arn: "my-role", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.AssumeRole.ARN = tt.arn
+
+			err := cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestConfigValidate_Required(t *testing.T) {
+	cfg := validConfig()
+	assert.NoError(t, cfg.Validate())
+
+	cfg = validConfig()
+	cfg.LogGroupName = ""
+	assert.Error(t, cfg.Validate())
+
+	cfg = validConfig()
+	cfg.LogStreamName = ""
+	assert.Error(t, cfg.Validate())
+
+	cfg = validConfig()
+	cfg.QueueSettings.QueueSize = 0
+	assert.Error(t, cfg.Validate())
+
+	cfg = validConfig()
+	cfg.MaxCacheSize = 0
+	assert.Error(t, cfg.Validate())
+}
+
+func TestConfigValidate_PersistentQueueRequiresStorageID(t *testing.T) {
+	cfg := validConfig()
+	cfg.QueueSettings.Persistent = true
+	assert.Error(t, cfg.Validate(), "'sending_queue.storage' must be set when persistent is true")
+
+	cfg.QueueSettings.StorageID = component.NewID(component.MustNewType("file_storage"))
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestEnforcedQueueSettings_PropagatesStorageID(t *testing.T) {
+	cfg := validConfig()
+	cfg.QueueSettings.QueueSize = 7
+
+	qs := cfg.enforcedQueueSettings()
+	assert.Equal(t, 7, qs.QueueSize)
+	assert.Equal(t, 1, qs.NumConsumers, "CloudWatch sequence tokens allow only one in-flight request")
+	assert.Nil(t, qs.StorageID)
+
+	storageID := component.NewID(component.MustNewType("file_storage"))
+	cfg.QueueSettings.Persistent = true
+	cfg.QueueSettings.StorageID = storageID
+
+	qs = cfg.enforcedQueueSettings()
+	require.NotNil(t, qs.StorageID)
+	assert.Equal(t, storageID, *qs.StorageID)
+}
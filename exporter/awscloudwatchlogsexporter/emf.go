@@ -0,0 +1,148 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awscloudwatchlogsexporter"
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// emfMetricDirective describes one entry in the _aws.CloudWatchMetrics
+// envelope: https://docs.aws.amazon.com/AmazonCloudWatch/latest/monitoring/CloudWatch_Embedded_Metric_Format_Specification.html
+type emfMetricDirective struct {
+	Namespace  string          `json:"Namespace"`
+	Dimensions [][]string      `json:"Dimensions"`
+	Metrics    []emfMetricName `json:"Metrics"`
+}
+
+type emfMetricName struct {
+	Name string `json:"Name"`
+}
+
+type emfEnvelope struct {
+	Timestamp         int64                `json:"Timestamp"`
+	CloudWatchMetrics []emfMetricDirective `json:"CloudWatchMetrics"`
+}
+
+// buildEMFDocument assembles a single CloudWatch EMF JSON document out of
+// one or more numeric metric fields and the attributes (used both as EMF
+// dimensions and as plain top-level fields) attached to them.
+func buildEMFDocument(namespace string, dimensionSets [][]string, metricNames []string, fields map[string]interface{}, timestampMillis int64) ([]byte, error) {
+	// CloudWatch requires "Dimensions" to be present as an array, even when
+	// empty - never serialize it as JSON null.
+	if dimensionSets == nil {
+		dimensionSets = [][]string{}
+	}
+	directive := emfMetricDirective{
+		Namespace:  namespace,
+		Dimensions: dimensionSets,
+	}
+	for _, name := range metricNames {
+		directive.Metrics = append(directive.Metrics, emfMetricName{Name: name})
+	}
+
+	doc := make(map[string]interface{}, len(fields)+1)
+	for k, v := range fields {
+		doc[k] = v
+	}
+	doc["_aws"] = emfEnvelope{
+		Timestamp:         timestampMillis,
+		CloudWatchMetrics: []emfMetricDirective{directive},
+	}
+
+	return json.Marshal(doc)
+}
+
+// fieldsContainAll reports whether fields has an entry for every key in
+// dimensionSet.
+func fieldsContainAll(dimensionSet []string, fields map[string]interface{}) bool {
+	for _, key := range dimensionSet {
+		if _, ok := fields[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// applicableDimensionSets returns only the configured dimension sets whose
+// keys are all present in fields, since CloudWatch rejects a dimension set
+// that references a missing field.
+func applicableDimensionSets(dimensionSets [][]string, fields map[string]interface{}) [][]string {
+	var applicable [][]string
+	for _, set := range dimensionSets {
+		if fieldsContainAll(set, fields) {
+			applicable = append(applicable, set)
+		}
+	}
+	return applicable
+}
+
+// logRecordToEvent serializes a log record as an EMF document when EMF
+// emission is enabled and the record carries numeric attributes listed in
+// MetricNameKeys, falling back to the plain log line otherwise.
+func (e *cwLogsExporter) logRecordToEvent(lr plog.LogRecord, resource pcommon.Map) (*cloudwatchlogs.InputLogEvent, error) {
+	if !e.Config.EMF.Enabled {
+		return e.mapLogRecordToEvent(lr)
+	}
+
+	fields := map[string]interface{}{}
+	resource.Range(func(k string, v pcommon.Value) bool {
+		fields[k] = v.AsRaw()
+		return true
+	})
+	lr.Attributes().Range(func(k string, v pcommon.Value) bool {
+		fields[k] = v.AsRaw()
+		return true
+	})
+
+	metricNames := numericMetricNames(e.Config.EMF.MetricNameKeys, fields)
+	if len(metricNames) == 0 {
+		return e.mapLogRecordToEvent(lr)
+	}
+
+	ts := int64(lr.Timestamp()) / 1e6
+	if ts == 0 {
+		ts = int64(lr.ObservedTimestamp()) / 1e6
+	}
+
+	dimensionSets := applicableDimensionSets(e.Config.EMF.Dimensions, fields)
+	doc, err := buildEMFDocument(e.Config.EMF.Namespace, dimensionSets, metricNames, fields, ts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build EMF document: %w", err)
+	}
+
+	return &cloudwatchlogs.InputLogEvent{
+		Timestamp: aws.Int64(ts),
+		Message:   aws.String(string(doc)),
+	}, nil
+}
+
+// numericMetricNames returns the subset of metricNameKeys whose value in
+// fields is actually numeric, since CloudWatch metrics must be numbers.
+func numericMetricNames(metricNameKeys []string, fields map[string]interface{}) []string {
+	var names []string
+	for _, key := range metricNameKeys {
+		switch fields[key].(type) {
+		case int64, float64, int, float32:
+			names = append(names, key)
+		}
+	}
+	return names
+}
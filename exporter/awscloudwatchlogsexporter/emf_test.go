@@ -0,0 +1,81 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildEMFDocument_Envelope(t *testing.T) {
+	fields := map[string]interface{}{"service.name": "my-service", "latency_ms": 42.0}
+	doc, err := buildEMFDocument("MyApp", [][]string{{"service.name"}}, []string{"latency_ms"}, fields, 1700000000000)
+	require.NoError(t, err)
+
+	var parsed map[string]interface{}
+	require.NoError(t, json.Unmarshal(doc, &parsed))
+
+	assert.Equal(t, "my-service", parsed["service.name"])
+	assert.Equal(t, 42.0, parsed["latency_ms"])
+
+	aws, ok := parsed["_aws"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, 1700000000000.0, aws["Timestamp"])
+
+	directives, ok := aws["CloudWatchMetrics"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, directives, 1)
+	directive := directives[0].(map[string]interface{})
+	assert.Equal(t, "MyApp", directive["Namespace"])
+	assert.Equal(t, [][]interface{}{{"service.name"}}, toDimensions(directive["Dimensions"]))
+}
+
+func TestBuildEMFDocument_NoApplicableDimensionsSerializesEmptyArray(t *testing.T) {
+	// applicableDimensionSets returns nil when no configured set is fully
+	// present in fields; buildEMFDocument must still emit "Dimensions":[],
+	// never "Dimensions":null, since CloudWatch requires an array.
+	fields := map[string]interface{}{"latency_ms": 42.0}
+	dimensionSets := applicableDimensionSets([][]string{{"service.name"}}, fields)
+	require.Nil(t, dimensionSets)
+
+	doc, err := buildEMFDocument("MyApp", dimensionSets, []string{"latency_ms"}, fields, 1700000000000)
+	require.NoError(t, err)
+	assert.Contains(t, string(doc), `"Dimensions":[]`)
+	assert.NotContains(t, string(doc), `"Dimensions":null`)
+}
+
+func toDimensions(v interface{}) [][]interface{} {
+	raw := v.([]interface{})
+	out := make([][]interface{}, len(raw))
+	for i, set := range raw {
+		out[i] = set.([]interface{})
+	}
+	return out
+}
+
+func TestApplicableDimensionSets(t *testing.T) {
+	fields := map[string]interface{}{"service.name": "svc", "host.name": "host"}
+
+	applicable := applicableDimensionSets([][]string{
+		{"service.name"},
+		{"service.name", "host.name"},
+		{"missing.key"},
+	}, fields)
+
+	assert.Equal(t, [][]string{{"service.name"}, {"service.name", "host.name"}}, applicable)
+}
@@ -0,0 +1,201 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awscloudwatchlogsexporter"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap"
+)
+
+// cwLogsExporter pushes OTLP logs to CloudWatch Logs, resolving the
+// destination log group and log stream per log record from (possibly
+// templated) config, assuming an IAM role first when configured to do so.
+// client is the cloudwatchlogsiface.CloudWatchLogsAPI interface, rather than
+// the concrete SDK type, so tests can substitute a fake without talking to
+// AWS.
+type cwLogsExporter struct {
+	Config *Config
+	logger *zap.Logger
+	client cloudwatchlogsiface.CloudWatchLogsAPI
+
+	logGroupTemplate  *nameTemplate
+	logStreamTemplate *nameTemplate
+
+	streams   *streamCache
+	batcher   *batcher
+	telemetry *batcherTelemetry
+}
+
+func newCwLogsExporter(config *Config, set exporter.CreateSettings) (*cwLogsExporter, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	sess, err := newAWSSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+	}
+
+	logGroupTemplate, err := parseNameTemplate(config.LogGroupName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'log_group_name' template: %w", err)
+	}
+	logStreamTemplate, err := parseNameTemplate(config.LogStreamName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'log_stream_name' template: %w", err)
+	}
+
+	client := cloudwatchlogs.New(sess)
+
+	meter := set.TelemetrySettings.MeterProvider.Meter(typeStr)
+	telemetry, err := newBatcherTelemetry(meter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create batcher telemetry: %w", err)
+	}
+
+	return &cwLogsExporter{
+		Config:            config,
+		logger:            set.TelemetrySettings.Logger,
+		client:            client,
+		logGroupTemplate:  logGroupTemplate,
+		logStreamTemplate: logStreamTemplate,
+		streams:           newStreamCache(config.MaxCacheSize),
+		batcher:           newBatcher(),
+		telemetry:         telemetry,
+	}, nil
+}
+
+// newAWSSession builds the AWS session used to talk to CloudWatch Logs,
+// optionally assuming config.AssumeRole.ARN via AWS STS so that a single
+// collector can deliver logs to log groups owned by other AWS accounts.
+func newAWSSession(config *Config) (*session.Session, error) {
+	awsCfg := aws.NewConfig()
+	if config.Region != "" {
+		awsCfg = awsCfg.WithRegion(config.Region)
+	}
+	if config.Endpoint != "" {
+		awsCfg = awsCfg.WithEndpoint(config.Endpoint)
+	}
+
+	sess, err := session.NewSession(awsCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.AssumeRole.ARN == "" {
+		return sess, nil
+	}
+
+	creds := stscreds.NewCredentials(sess, config.AssumeRole.ARN, func(p *stscreds.AssumeRoleProvider) {
+		if config.AssumeRole.ExternalID != "" {
+			p.ExternalID = aws.String(config.AssumeRole.ExternalID)
+		}
+		if config.AssumeRole.SessionName != "" {
+			p.RoleSessionName = config.AssumeRole.SessionName
+		}
+		if config.AssumeRole.SessionDuration > 0 {
+			p.Duration = config.AssumeRole.SessionDuration
+		}
+	})
+
+	// Credentials are refreshed automatically by the returned provider as
+	// they approach expiry, so the session can be reused for the lifetime of
+	// the exporter.
+	return session.NewSession(awsCfg.WithCredentials(creds))
+}
+
+func (e *cwLogsExporter) pushLogs(_ context.Context, ld plog.Logs) error {
+	destEvents, err := e.groupByDestination(ld)
+	if err != nil {
+		return err
+	}
+	for dest, events := range destEvents {
+		if err := e.sendEvents(dest.logGroupName, dest.logStreamName, events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendEvents bin-packs events into PutLogEvents-sized batches via e.batcher,
+// reporting the awscwlogs_batch_size_bytes/awscwlogs_batch_event_count/
+// awscwlogs_dropped_events_total metrics along the way, and sends each
+// resulting batch to the given destination in order.
+func (e *cwLogsExporter) sendEvents(logGroupName, logStreamName string, events []*cloudwatchlogs.InputLogEvent) error {
+	batches, dropped := e.batcher.batch(events)
+	e.telemetry.recordDropped(dropped)
+	for _, batch := range batches {
+		e.telemetry.recordBatch(batch)
+		if err := e.putLogEvents(logGroupName, logStreamName, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// destination identifies a resolved (log group, log stream) pair that a
+// batch of log records should be delivered to.
+type destination struct {
+	logGroupName  string
+	logStreamName string
+}
+
+// groupByDestination resolves the LogGroupName/LogStreamName templates for
+// every log record and groups the resulting CloudWatch Logs events by
+// destination, since a single PutLogEvents call can only target one log
+// group and log stream.
+func (e *cwLogsExporter) groupByDestination(ld plog.Logs) (map[destination][]*cloudwatchlogs.InputLogEvent, error) {
+	batches := make(map[destination][]*cloudwatchlogs.InputLogEvent)
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resource := rl.Resource().Attributes()
+		sls := rl.ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			lrs := sls.At(j).LogRecords()
+			for k := 0; k < lrs.Len(); k++ {
+				lr := lrs.At(k)
+				logGroupName, err := e.logGroupTemplate.resolve(resource, lr.Attributes())
+				if err != nil {
+					return nil, fmt.Errorf("failed to resolve 'log_group_name': %w", err)
+				}
+				logStreamName, err := e.logStreamTemplate.resolve(resource, lr.Attributes())
+				if err != nil {
+					return nil, fmt.Errorf("failed to resolve 'log_stream_name': %w", err)
+				}
+				dest := destination{logGroupName: logGroupName, logStreamName: logStreamName}
+				event, err := e.logRecordToEvent(lr, resource)
+				if err != nil {
+					return nil, err
+				}
+				batches[dest] = append(batches[dest], event)
+			}
+		}
+	}
+	return batches, nil
+}
+
+func (e *cwLogsExporter) shutdown(_ context.Context) error {
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestNewAWSSession_NoAssumeRole(t *testing.T) {
+	cfg := validConfig()
+	cfg.Region = "us-east-1"
+
+	sess, err := newAWSSession(cfg)
+	require.NoError(t, err)
+	assert.Nil(t, sess.Config.Credentials)
+}
+
+func TestNewAWSSession_AssumeRole(t *testing.T) {
+	cfg := validConfig()
+	cfg.Region = "us-east-1"
+	cfg.AssumeRole.ARN = "arn:aws:iam::123456789012:role/my-role"
+	cfg.AssumeRole.ExternalID = "external-id"
+	cfg.AssumeRole.SessionName = "my-session"
+
+	sess, err := newAWSSession(cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, sess.Config.Credentials)
+}
+
+func TestPushLogs_RoutesToDestination(t *testing.T) {
+	client := newFakeCWLogsClient()
+	cfg := validConfig()
+	exp := newTestExporter(t, cfg, client)
+
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.Body().SetStr("hello")
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+
+	require.NoError(t, exp.pushLogs(context.Background(), logs))
+	assert.Equal(t, 1, client.callCount())
+}
@@ -0,0 +1,99 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awscloudwatchlogsexporter"
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+const (
+	typeStr = "awscloudwatchlogs"
+)
+
+// NewFactory creates a factory for the AWS CloudWatch Logs exporter.
+func NewFactory() exporter.Factory {
+	return exporter.NewFactory(
+		component.MustNewType(typeStr),
+		createDefaultConfig,
+		exporter.WithLogs(createLogsExporter, component.StabilityLevelBeta),
+		exporter.WithMetrics(createMetricsExporter, component.StabilityLevelAlpha))
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		RetrySettings: exporterhelper.NewDefaultRetrySettings(),
+		QueueSettings: QueueSettings{QueueSize: 5},
+		MaxCacheSize:  1000,
+	}
+}
+
+func createLogsExporter(
+	ctx context.Context,
+	set exporter.CreateSettings,
+	cfg component.Config,
+) (exporter.Logs, error) {
+	expCfg := cfg.(*Config)
+	expCfg.logger = set.TelemetrySettings.Logger
+
+	exp, err := newCwLogsExporter(expCfg, set)
+	if err != nil {
+		return nil, err
+	}
+	return exporterhelper.NewLogsExporter(
+		ctx,
+		set,
+		cfg,
+		exp.pushLogs,
+		exporterhelper.WithQueue(expCfg.enforcedQueueSettings()),
+		exporterhelper.WithRetry(expCfg.RetrySettings),
+		exporterhelper.WithShutdown(exp.shutdown),
+	)
+}
+
+// createMetricsExporter lets this exporter accept a metrics pipeline
+// directly, emitting each numeric data point as a CloudWatch EMF document.
+// It requires 'emf.enabled' to be set, since plain CloudWatch Logs has no
+// native representation for metrics.
+func createMetricsExporter(
+	ctx context.Context,
+	set exporter.CreateSettings,
+	cfg component.Config,
+) (exporter.Metrics, error) {
+	expCfg := cfg.(*Config)
+	expCfg.logger = set.TelemetrySettings.Logger
+
+	if !expCfg.EMF.Enabled {
+		return nil, errors.New("'emf.enabled' must be true to use the awscloudwatchlogs exporter with a metrics pipeline")
+	}
+
+	exp, err := newCwLogsExporter(expCfg, set)
+	if err != nil {
+		return nil, err
+	}
+	return exporterhelper.NewMetricsExporter(
+		ctx,
+		set,
+		cfg,
+		exp.pushMetrics,
+		exporterhelper.WithQueue(expCfg.enforcedQueueSettings()),
+		exporterhelper.WithRetry(expCfg.RetrySettings),
+		exporterhelper.WithShutdown(exp.shutdown),
+	)
+}
@@ -0,0 +1,165 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap"
+)
+
+// fakeCWLogsClient is an in-memory cloudwatchlogsiface.CloudWatchLogsAPI
+// used to exercise the exporter without talking to AWS. Embedding the
+// interface means any method this package doesn't call panics with a nil
+// pointer dereference if it's ever invoked, which is the intended failure
+// mode for an incomplete test double.
+type fakeCWLogsClient struct {
+	cloudwatchlogsiface.CloudWatchLogsAPI
+
+	mu sync.Mutex
+
+	groups  map[string]bool
+	streams map[string]bool
+	tokens  map[string]string
+
+	putLogEvents []*cloudwatchlogs.PutLogEventsInput
+
+	// putLogEventsErr, when set, is returned by the next PutLogEvents call
+	// instead of succeeding; it is cleared after being returned once.
+	putLogEventsErr error
+}
+
+func newFakeCWLogsClient() *fakeCWLogsClient {
+	return &fakeCWLogsClient{
+		groups:  make(map[string]bool),
+		streams: make(map[string]bool),
+		tokens:  make(map[string]string),
+	}
+}
+
+func (f *fakeCWLogsClient) CreateLogGroup(in *cloudwatchlogs.CreateLogGroupInput) (*cloudwatchlogs.CreateLogGroupOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := aws.StringValue(in.LogGroupName)
+	if f.groups[name] {
+		return nil, awserr.New(cloudwatchlogs.ErrCodeResourceAlreadyExistsException, "already exists", nil)
+	}
+	f.groups[name] = true
+	return &cloudwatchlogs.CreateLogGroupOutput{}, nil
+}
+
+func (f *fakeCWLogsClient) CreateLogStream(in *cloudwatchlogs.CreateLogStreamInput) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := aws.StringValue(in.LogGroupName) + "\x00" + aws.StringValue(in.LogStreamName)
+	if f.streams[key] {
+		return nil, awserr.New(cloudwatchlogs.ErrCodeResourceAlreadyExistsException, "already exists", nil)
+	}
+	f.streams[key] = true
+	return &cloudwatchlogs.CreateLogStreamOutput{}, nil
+}
+
+func (f *fakeCWLogsClient) PutRetentionPolicy(*cloudwatchlogs.PutRetentionPolicyInput) (*cloudwatchlogs.PutRetentionPolicyOutput, error) {
+	return &cloudwatchlogs.PutRetentionPolicyOutput{}, nil
+}
+
+func (f *fakeCWLogsClient) DescribeLogStreams(in *cloudwatchlogs.DescribeLogStreamsInput) (*cloudwatchlogs.DescribeLogStreamsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := aws.StringValue(in.LogStreamNamePrefix)
+	key := aws.StringValue(in.LogGroupName) + "\x00" + name
+	if !f.streams[key] {
+		return &cloudwatchlogs.DescribeLogStreamsOutput{}, nil
+	}
+	var token *string
+	if t, ok := f.tokens[key]; ok {
+		token = aws.String(t)
+	}
+	return &cloudwatchlogs.DescribeLogStreamsOutput{
+		LogStreams: []*cloudwatchlogs.LogStream{
+			{LogStreamName: aws.String(name), UploadSequenceToken: token},
+		},
+	}, nil
+}
+
+func (f *fakeCWLogsClient) PutLogEvents(in *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.putLogEventsErr != nil {
+		err := f.putLogEventsErr
+		f.putLogEventsErr = nil
+		return nil, err
+	}
+
+	f.putLogEvents = append(f.putLogEvents, in)
+
+	key := aws.StringValue(in.LogGroupName) + "\x00" + aws.StringValue(in.LogStreamName)
+	next := aws.String(fmt.Sprintf("token-%d", len(f.putLogEvents)))
+	f.tokens[key] = aws.StringValue(next)
+	return &cloudwatchlogs.PutLogEventsOutput{NextSequenceToken: next}, nil
+}
+
+func (f *fakeCWLogsClient) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.putLogEvents)
+}
+
+// validConfig returns a Config that satisfies every Validate() requirement,
+// so individual tests only need to override the field(s) they care about.
+func validConfig() *Config {
+	return &Config{
+		LogGroupName:  "test-group",
+		LogStreamName: "test-stream",
+		MaxCacheSize:  1000,
+		QueueSettings: QueueSettings{QueueSize: 1},
+	}
+}
+
+// newTestExporter builds a cwLogsExporter wired to client, bypassing AWS
+// session/STS setup entirely, for tests that exercise pusher/batching/
+// mapping/EMF logic in isolation.
+func newTestExporter(t *testing.T, cfg *Config, client cloudwatchlogsiface.CloudWatchLogsAPI) *cwLogsExporter {
+	t.Helper()
+
+	logGroupTemplate, err := parseNameTemplate(cfg.LogGroupName)
+	require.NoError(t, err)
+	logStreamTemplate, err := parseNameTemplate(cfg.LogStreamName)
+	require.NoError(t, err)
+
+	meter := noop.NewMeterProvider().Meter("test")
+	telemetry, err := newBatcherTelemetry(meter)
+	require.NoError(t, err)
+
+	return &cwLogsExporter{
+		Config:            cfg,
+		logger:            zap.NewNop(),
+		client:            client,
+		logGroupTemplate:  logGroupTemplate,
+		logStreamTemplate: logStreamTemplate,
+		streams:           newStreamCache(cfg.MaxCacheSize),
+		batcher:           newBatcher(),
+		telemetry:         telemetry,
+	}
+}
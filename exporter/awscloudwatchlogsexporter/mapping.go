@@ -0,0 +1,199 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awscloudwatchlogsexporter"
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// mapLogRecordToEvent turns a log record into a CloudWatch Logs event using
+// the exporter's Mapping config: which timestamp to use, which attributes to
+// keep and promote, and whether to serialize the body as JSON or logfmt.
+// RawLog skips attribute filtering and serialization and forwards the body
+// as is; the timestamp is still chosen by Mapping.TimestampSource.
+func (e *cwLogsExporter) mapLogRecordToEvent(lr plog.LogRecord) (*cloudwatchlogs.InputLogEvent, error) {
+	ts := resolveTimestamp(lr, e.Config.Mapping.TimestampSource)
+
+	if e.Config.RawLog {
+		return &cloudwatchlogs.InputLogEvent{
+			Timestamp: aws.Int64(ts),
+			Message:   aws.String(lr.Body().AsString()),
+		}, nil
+	}
+
+	attrs := filteredAttributes(lr.Attributes(), e.Config.Mapping.DropAttributes, e.Config.Mapping.KeepAttributes)
+
+	var message string
+	switch e.Config.Mapping.Format {
+	case mappingFormatLogfmt:
+		message = encodeLogfmt(lr.Body().AsString(), attrs)
+	default:
+		doc, err := encodeJSONEvent(lr.Body().AsString(), attrs, e.Config.Mapping.PromoteAttributes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal log event: %w", err)
+		}
+		message = string(doc)
+	}
+
+	return &cloudwatchlogs.InputLogEvent{
+		Timestamp: aws.Int64(ts),
+		Message:   aws.String(message),
+	}, nil
+}
+
+// resolveTimestamp picks the CloudWatch event timestamp (ms since epoch)
+// according to source: "log.timestamp"/"" uses the record's own timestamp,
+// "observed_timestamp" uses when the collector observed it, and
+// "attribute:<key>" reads an attribute, falling back to the record's
+// timestamp when the attribute is absent or not a usable value.
+func resolveTimestamp(lr plog.LogRecord, source string) int64 {
+	fallback := func() int64 {
+		ts := int64(lr.Timestamp()) / 1e6
+		if ts == 0 {
+			ts = int64(lr.ObservedTimestamp()) / 1e6
+		}
+		return ts
+	}
+
+	switch {
+	case source == timestampSourceObservedTimestamp:
+		return int64(lr.ObservedTimestamp()) / 1e6
+	case strings.HasPrefix(source, attributeTimestampSourcePrefix):
+		key := strings.TrimPrefix(source, attributeTimestampSourcePrefix)
+		if v, ok := lr.Attributes().Get(key); ok {
+			if ms, ok := attributeToUnixMilli(v); ok {
+				return ms
+			}
+		}
+		return fallback()
+	default:
+		return fallback()
+	}
+}
+
+func attributeToUnixMilli(v pcommon.Value) (int64, bool) {
+	switch v.Type() {
+	case pcommon.ValueTypeInt:
+		return v.Int(), true
+	case pcommon.ValueTypeDouble:
+		return int64(v.Double()), true
+	case pcommon.ValueTypeStr:
+		if t, err := time.Parse(time.RFC3339Nano, v.Str()); err == nil {
+			return t.UnixMilli(), true
+		}
+	}
+	return 0, false
+}
+
+// filteredAttributes applies DropAttributes/KeepAttributes - at most one of
+// which is set, enforced by Config.Validate - and returns the surviving
+// attributes as a plain map, ready for serialization.
+func filteredAttributes(attrs pcommon.Map, drop, keep []string) map[string]interface{} {
+	var keepSet, dropSet map[string]struct{}
+	if len(keep) > 0 {
+		keepSet = toSet(keep)
+	}
+	if len(drop) > 0 {
+		dropSet = toSet(drop)
+	}
+
+	filtered := make(map[string]interface{})
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		if keepSet != nil {
+			if _, ok := keepSet[k]; !ok {
+				return true
+			}
+		}
+		if dropSet != nil {
+			if _, ok := dropSet[k]; ok {
+				return true
+			}
+		}
+		filtered[k] = v.AsRaw()
+		return true
+	})
+	return filtered
+}
+
+func toSet(keys []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		set[k] = struct{}{}
+	}
+	return set
+}
+
+// encodeJSONEvent builds the JSON document CloudWatch Logs stores for a log
+// record: the body, plus attributes nested under "attributes" unless their
+// key is listed in promote, in which case they become top-level fields.
+func encodeJSONEvent(body string, attrs map[string]interface{}, promote []string) ([]byte, error) {
+	doc := map[string]interface{}{"body": body}
+	promoteSet := toSet(promote)
+
+	var nested map[string]interface{}
+	for k, v := range attrs {
+		if _, ok := promoteSet[k]; ok {
+			doc[k] = v
+			continue
+		}
+		if nested == nil {
+			nested = make(map[string]interface{}, len(attrs))
+		}
+		nested[k] = v
+	}
+	if nested != nil {
+		doc["attributes"] = nested
+	}
+
+	return json.Marshal(doc)
+}
+
+// encodeLogfmt renders the body and attributes as space-separated
+// key=value pairs, quoting any value containing whitespace.
+func encodeLogfmt(body string, attrs map[string]interface{}) string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString("body=")
+	sb.WriteString(logfmtValue(body))
+	for _, k := range keys {
+		sb.WriteByte(' ')
+		sb.WriteString(k)
+		sb.WriteByte('=')
+		sb.WriteString(logfmtValue(fmt.Sprintf("%v", attrs[k])))
+	}
+	return sb.String()
+}
+
+func logfmtValue(s string) string {
+	if strings.ContainsAny(s, " \t\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,170 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func newLogRecord(t *testing.T) plog.LogRecord {
+	t.Helper()
+	lr := plog.NewLogRecord()
+	lr.Body().SetStr("hello world")
+	lr.SetTimestamp(1700000000000000000)
+	lr.SetObservedTimestamp(1700000001000000000)
+	return lr
+}
+
+func TestMapLogRecordToEvent_RawLog(t *testing.T) {
+	exp := newTestExporter(t, validConfig(), newFakeCWLogsClient())
+	exp.Config.RawLog = true
+
+	lr := newLogRecord(t)
+	event, err := exp.mapLogRecordToEvent(lr)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", aws.StringValue(event.Message))
+}
+
+func TestMapLogRecordToEvent_JSONFormat(t *testing.T) {
+	cfg := validConfig()
+	exp := newTestExporter(t, cfg, newFakeCWLogsClient())
+
+	lr := newLogRecord(t)
+	lr.Attributes().PutStr("service.name", "my-service")
+	lr.Attributes().PutInt("retries", 2)
+
+	event, err := exp.mapLogRecordToEvent(lr)
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(aws.StringValue(event.Message)), &doc))
+	assert.Equal(t, "hello world", doc["body"])
+	attrs := doc["attributes"].(map[string]interface{})
+	assert.Equal(t, "my-service", attrs["service.name"])
+	assert.Equal(t, 2.0, attrs["retries"])
+}
+
+func TestMapLogRecordToEvent_JSONFormatPromotesAttributes(t *testing.T) {
+	cfg := validConfig()
+	cfg.Mapping.PromoteAttributes = []string{"service.name"}
+	exp := newTestExporter(t, cfg, newFakeCWLogsClient())
+
+	lr := newLogRecord(t)
+	lr.Attributes().PutStr("service.name", "my-service")
+	lr.Attributes().PutStr("env", "prod")
+
+	event, err := exp.mapLogRecordToEvent(lr)
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(aws.StringValue(event.Message)), &doc))
+	assert.Equal(t, "my-service", doc["service.name"])
+	attrs := doc["attributes"].(map[string]interface{})
+	assert.Equal(t, "prod", attrs["env"])
+	_, promotedAlsoNested := attrs["service.name"]
+	assert.False(t, promotedAlsoNested)
+}
+
+func TestMapLogRecordToEvent_LogfmtFormat(t *testing.T) {
+	cfg := validConfig()
+	cfg.Mapping.Format = mappingFormatLogfmt
+	exp := newTestExporter(t, cfg, newFakeCWLogsClient())
+
+	lr := newLogRecord(t)
+	lr.Attributes().PutStr("service.name", "my service")
+
+	event, err := exp.mapLogRecordToEvent(lr)
+	require.NoError(t, err)
+	assert.Equal(t, `body="hello world" service.name="my service"`, aws.StringValue(event.Message))
+}
+
+func TestMapLogRecordToEvent_KeepAttributes(t *testing.T) {
+	cfg := validConfig()
+	cfg.Mapping.Format = mappingFormatLogfmt
+	cfg.Mapping.KeepAttributes = []string{"keep_me"}
+	exp := newTestExporter(t, cfg, newFakeCWLogsClient())
+
+	lr := newLogRecord(t)
+	lr.Attributes().PutStr("keep_me", "yes")
+	lr.Attributes().PutStr("drop_me", "no")
+
+	event, err := exp.mapLogRecordToEvent(lr)
+	require.NoError(t, err)
+	assert.Equal(t, `body="hello world" keep_me=yes`, aws.StringValue(event.Message))
+}
+
+func TestMapLogRecordToEvent_DropAttributes(t *testing.T) {
+	cfg := validConfig()
+	cfg.Mapping.Format = mappingFormatLogfmt
+	cfg.Mapping.DropAttributes = []string{"drop_me"}
+	exp := newTestExporter(t, cfg, newFakeCWLogsClient())
+
+	lr := newLogRecord(t)
+	lr.Attributes().PutStr("keep_me", "yes")
+	lr.Attributes().PutStr("drop_me", "no")
+
+	event, err := exp.mapLogRecordToEvent(lr)
+	require.NoError(t, err)
+	assert.Equal(t, `body="hello world" keep_me=yes`, aws.StringValue(event.Message))
+}
+
+func TestResolveTimestamp_Sources(t *testing.T) {
+	lr := newLogRecord(t)
+
+	assert.Equal(t, int64(1700000000000), resolveTimestamp(lr, ""))
+	assert.Equal(t, int64(1700000000000), resolveTimestamp(lr, timestampSourceLogTimestamp))
+	assert.Equal(t, int64(1700000001000), resolveTimestamp(lr, timestampSourceObservedTimestamp))
+}
+
+func TestResolveTimestamp_FromAttribute(t *testing.T) {
+	lr := newLogRecord(t)
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lr.Attributes().PutStr("custom_ts", at.Format(time.RFC3339Nano))
+
+	ts := resolveTimestamp(lr, attributeTimestampSourcePrefix+"custom_ts")
+	assert.Equal(t, at.UnixMilli(), ts)
+}
+
+func TestResolveTimestamp_FromAttributeFallsBackWhenMissing(t *testing.T) {
+	lr := newLogRecord(t)
+	ts := resolveTimestamp(lr, attributeTimestampSourcePrefix+"missing")
+	assert.Equal(t, int64(1700000000000), ts)
+}
+
+func TestMappingValidate_MutuallyExclusiveFilters(t *testing.T) {
+	cfg := validConfig()
+	cfg.Mapping.DropAttributes = []string{"a"}
+	cfg.Mapping.KeepAttributes = []string{"b"}
+	assert.Error(t, cfg.Validate())
+}
+
+func TestMappingValidate_InvalidTimestampSource(t *testing.T) {
+	cfg := validConfig()
+	cfg.Mapping.TimestampSource = "bogus"
+	assert.Error(t, cfg.Validate())
+}
+
+func TestMappingValidate_InvalidFormat(t *testing.T) {
+	cfg := validConfig()
+	cfg.Mapping.Format = "xml"
+	assert.Error(t, cfg.Validate())
+}
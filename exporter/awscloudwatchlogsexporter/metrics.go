@@ -0,0 +1,138 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awscloudwatchlogsexporter"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+// pushMetrics emits each numeric data point in md as a CloudWatch EMF
+// document, so the exporter can be wired directly into a metrics pipeline
+// instead of only receiving numeric attributes on logs.
+func (e *cwLogsExporter) pushMetrics(_ context.Context, md pmetric.Metrics) error {
+	batches := make(map[destination][]*cloudwatchlogs.InputLogEvent)
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resource := rm.Resource().Attributes()
+		sms := rm.ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			ms := sms.At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				metric := ms.At(k)
+				events, err := e.metricToEMFEvents(metric, resource)
+				if err != nil {
+					return err
+				}
+				for dest, destEvents := range events {
+					batches[dest] = append(batches[dest], destEvents...)
+				}
+			}
+		}
+	}
+
+	for dest, events := range batches {
+		if err := e.sendEvents(dest.logGroupName, dest.logStreamName, events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// metricToEMFEvents builds one EMF document per numeric data point of
+// metric, grouped by the destination its attributes resolve to. Multiple
+// data points commonly resolve to the same destination (e.g. static or
+// resource-only templates), so each destination accumulates a slice rather
+// than a single event.
+func (e *cwLogsExporter) metricToEMFEvents(metric pmetric.Metric, resource pcommon.Map) (map[destination][]*cloudwatchlogs.InputLogEvent, error) {
+	points := e.numberDataPoints(metric)
+	events := make(map[destination][]*cloudwatchlogs.InputLogEvent, len(points))
+
+	for _, dp := range points {
+		fields := map[string]interface{}{}
+		resource.Range(func(k string, v pcommon.Value) bool {
+			fields[k] = v.AsRaw()
+			return true
+		})
+		dp.Attributes().Range(func(k string, v pcommon.Value) bool {
+			fields[k] = v.AsRaw()
+			return true
+		})
+		fields[metric.Name()] = numberDataPointValue(dp)
+
+		dimensionSets := applicableDimensionSets(e.Config.EMF.Dimensions, fields)
+		doc, err := buildEMFDocument(e.Config.EMF.Namespace, dimensionSets, []string{metric.Name()}, fields, int64(dp.Timestamp())/1e6)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build EMF document for metric %q: %w", metric.Name(), err)
+		}
+
+		logGroupName, err := e.logGroupTemplate.resolve(resource, dp.Attributes())
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve 'log_group_name': %w", err)
+		}
+		logStreamName, err := e.logStreamTemplate.resolve(resource, dp.Attributes())
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve 'log_stream_name': %w", err)
+		}
+
+		dest := destination{logGroupName: logGroupName, logStreamName: logStreamName}
+		events[dest] = append(events[dest], &cloudwatchlogs.InputLogEvent{
+			Timestamp: aws.Int64(int64(dp.Timestamp()) / 1e6),
+			Message:   aws.String(string(doc)),
+		})
+	}
+
+	return events, nil
+}
+
+// numberDataPoints returns the gauge/sum data points of metric; histograms,
+// summaries, and exponential histograms have no single numeric value and
+// are not supported by EMF emission, so they're dropped with a debug log
+// rather than silently.
+func (e *cwLogsExporter) numberDataPoints(metric pmetric.Metric) []pmetric.NumberDataPoint {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		return dataPointSlice(metric.Gauge().DataPoints())
+	case pmetric.MetricTypeSum:
+		return dataPointSlice(metric.Sum().DataPoints())
+	default:
+		e.logger.Debug("dropping metric unsupported by EMF emission",
+			zap.String("name", metric.Name()), zap.String("type", metric.Type().String()))
+		return nil
+	}
+}
+
+func dataPointSlice(dps pmetric.NumberDataPointSlice) []pmetric.NumberDataPoint {
+	points := make([]pmetric.NumberDataPoint, 0, dps.Len())
+	for i := 0; i < dps.Len(); i++ {
+		points = append(points, dps.At(i))
+	}
+	return points
+}
+
+func numberDataPointValue(dp pmetric.NumberDataPoint) float64 {
+	if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		return float64(dp.IntValue())
+	}
+	return dp.DoubleValue()
+}
@@ -0,0 +1,75 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestMetricToEMFEvents_MultiplePointsSameDestination is a regression test:
+// metricToEMFEvents used to key its result by destination alone, so data
+// points sharing a destination overwrote one another and only the last
+// survived. Every point must now produce its own event.
+func TestMetricToEMFEvents_MultiplePointsSameDestination(t *testing.T) {
+	cfg := validConfig()
+	cfg.EMF.Enabled = true
+	cfg.EMF.Namespace = "MyApp"
+	exp := newTestExporter(t, cfg, newFakeCWLogsClient())
+
+	metric := pmetric.NewMetric()
+	metric.SetName("requests")
+	gauge := metric.SetEmptyGauge()
+	for i := 0; i < 3; i++ {
+		dp := gauge.DataPoints().AppendEmpty()
+		dp.SetDoubleValue(float64(i))
+		dp.SetTimestamp(1700000000000000000)
+	}
+
+	events, err := exp.metricToEMFEvents(metric, pcommon.NewMap())
+	require.NoError(t, err)
+	require.Len(t, events, 1, "all three points resolve to the same static destination")
+
+	for _, destEvents := range events {
+		assert.Len(t, destEvents, 3, "every data point must produce its own event, not overwrite the others")
+	}
+}
+
+// TestNumberDataPoints_UnsupportedTypeLogsInsteadOfSilentlyDropping ensures
+// histograms/summaries/exponential histograms - which EMF can't represent as
+// a single numeric value - are at least logged when dropped, rather than
+// vanishing without a trace.
+func TestNumberDataPoints_UnsupportedTypeLogsInsteadOfSilentlyDropping(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	exp := newTestExporter(t, validConfig(), newFakeCWLogsClient())
+	exp.logger = zap.New(core)
+
+	metric := pmetric.NewMetric()
+	metric.SetName("latency")
+	metric.SetEmptyHistogram()
+
+	points := exp.numberDataPoints(metric)
+	assert.Nil(t, points)
+
+	entries := logs.FilterMessage("dropping metric unsupported by EMF emission").All()
+	require.Len(t, entries, 1)
+}
@@ -0,0 +1,185 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awscloudwatchlogsexporter"
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+)
+
+// streamState is the per-(log group, log stream) state the pusher needs to
+// keep around between calls: the CloudWatch Logs sequence token required by
+// the next PutLogEvents call. Whether the destination has already been
+// created - so LogRetention/Tags are applied only once - isn't cached here;
+// ensureStream detects that directly from AWS's
+// ErrCodeResourceAlreadyExistsException response instead.
+type streamState struct {
+	token *string
+}
+
+// streamCache is an LRU cache of streamState keyed by "logGroup\x00logStream".
+// Bounding it by MaxCacheSize keeps memory flat when LogGroupName/
+// LogStreamName templates produce high-cardinality destinations; evicted
+// entries are simply re-ensured (idempotently) the next time they're seen.
+type streamCache struct {
+	mu      sync.Mutex
+	maxSize int
+	ll      *list.List
+	items   map[string]*list.Element
+}
+
+type streamCacheEntry struct {
+	key   string
+	state *streamState
+}
+
+func newStreamCache(maxSize int) *streamCache {
+	return &streamCache{
+		maxSize: maxSize,
+		ll:      list.New(),
+		items:   make(map[string]*list.Element),
+	}
+}
+
+func streamCacheKey(logGroupName, logStreamName string) string {
+	return logGroupName + "\x00" + logStreamName
+}
+
+func (c *streamCache) get(key string) (*streamState, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(elem)
+	return elem.Value.(*streamCacheEntry).state, true
+}
+
+func (c *streamCache) put(key string, state *streamState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[key]; ok {
+		c.ll.MoveToFront(elem)
+		elem.Value.(*streamCacheEntry).state = state
+		return
+	}
+	elem := c.ll.PushFront(&streamCacheEntry{key: key, state: state})
+	c.items[key] = elem
+	if c.maxSize > 0 && c.ll.Len() > c.maxSize {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*streamCacheEntry).key)
+		}
+	}
+}
+
+// putLogEvents ensures the destination log group/stream exist - applying
+// LogRetention and Tags only the first time a destination is created - then
+// sends events via PutLogEvents, retrying once with a corrected sequence
+// token if AWS reports it's stale.
+func (e *cwLogsExporter) putLogEvents(logGroupName, logStreamName string, events []*cloudwatchlogs.InputLogEvent) error {
+	key := streamCacheKey(logGroupName, logStreamName)
+	state, ok := e.streams.get(key)
+	if !ok {
+		token, err := e.ensureStream(logGroupName, logStreamName)
+		if err != nil {
+			return err
+		}
+		state = &streamState{token: token}
+		e.streams.put(key, state)
+	}
+
+	input := &cloudwatchlogs.PutLogEventsInput{
+		LogGroupName:  aws.String(logGroupName),
+		LogStreamName: aws.String(logStreamName),
+		LogEvents:     events,
+		SequenceToken: state.token,
+	}
+
+	resp, err := e.client.PutLogEvents(input)
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == cloudwatchlogs.ErrCodeInvalidSequenceTokenException {
+			input.SequenceToken, err = e.describeSequenceToken(logGroupName, logStreamName)
+			if err != nil {
+				return err
+			}
+			resp, err = e.client.PutLogEvents(input)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	state.token = resp.NextSequenceToken
+	e.streams.put(key, state)
+	return nil
+}
+
+// ensureStream lazily creates the log group and log stream, applying
+// LogRetention and Tags to the group only when this call is the one that
+// creates it, and returns the stream's initial sequence token, if any.
+func (e *cwLogsExporter) ensureStream(logGroupName, logStreamName string) (*string, error) {
+	_, err := e.client.CreateLogGroup(&cloudwatchlogs.CreateLogGroupInput{
+		LogGroupName: aws.String(logGroupName),
+		Tags:         e.Config.Tags,
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != cloudwatchlogs.ErrCodeResourceAlreadyExistsException {
+			return nil, err
+		}
+	} else if e.Config.LogRetention > 0 {
+		if _, err := e.client.PutRetentionPolicy(&cloudwatchlogs.PutRetentionPolicyInput{
+			LogGroupName:    aws.String(logGroupName),
+			RetentionInDays: aws.Int64(e.Config.LogRetention),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = e.client.CreateLogStream(&cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  aws.String(logGroupName),
+		LogStreamName: aws.String(logStreamName),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != cloudwatchlogs.ErrCodeResourceAlreadyExistsException {
+			return nil, err
+		}
+		return e.describeSequenceToken(logGroupName, logStreamName)
+	}
+
+	return nil, nil
+}
+
+func (e *cwLogsExporter) describeSequenceToken(logGroupName, logStreamName string) (*string, error) {
+	resp, err := e.client.DescribeLogStreams(&cloudwatchlogs.DescribeLogStreamsInput{
+		LogGroupName:        aws.String(logGroupName),
+		LogStreamNamePrefix: aws.String(logStreamName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, stream := range resp.LogStreams {
+		if aws.StringValue(stream.LogStreamName) == logStreamName {
+			return stream.UploadSequenceToken, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := newStreamCache(2)
+	c.put("a", &streamState{})
+	c.put("b", &streamState{})
+	c.put("a", &streamState{}) // touch "a", making "b" the LRU entry
+	c.put("c", &streamState{}) // evicts "b"
+
+	_, ok := c.get("b")
+	assert.False(t, ok)
+	_, ok = c.get("a")
+	assert.True(t, ok)
+	_, ok = c.get("c")
+	assert.True(t, ok)
+}
+
+func TestPutLogEvents_CreatesDestinationOnce(t *testing.T) {
+	client := newFakeCWLogsClient()
+	exp := newTestExporter(t, validConfig(), client)
+
+	events := []*cloudwatchlogs.InputLogEvent{{Timestamp: aws.Int64(1), Message: aws.String("one")}}
+	require.NoError(t, exp.putLogEvents("group", "stream", events))
+	require.NoError(t, exp.putLogEvents("group", "stream", events))
+
+	assert.True(t, client.groups["group"])
+	assert.True(t, client.streams["group\x00stream"])
+	assert.Equal(t, 2, client.callCount())
+}
+
+func TestPutLogEvents_RetriesOnStaleSequenceToken(t *testing.T) {
+	client := newFakeCWLogsClient()
+	exp := newTestExporter(t, validConfig(), client)
+
+	// Seed the cache with a token the fake client no longer recognizes, to
+	// force the invalid-sequence-token retry path.
+	exp.streams.put(streamCacheKey("group", "stream"), &streamState{token: aws.String("stale-token")})
+	client.groups["group"] = true
+	client.streams["group\x00stream"] = true
+	client.tokens["group\x00stream"] = "current-token"
+	client.putLogEventsErr = awserr.New(cloudwatchlogs.ErrCodeInvalidSequenceTokenException, "stale", nil)
+
+	events := []*cloudwatchlogs.InputLogEvent{{Timestamp: aws.Int64(1), Message: aws.String("one")}}
+	require.NoError(t, exp.putLogEvents("group", "stream", events))
+
+	assert.Equal(t, 1, client.callCount())
+}
@@ -0,0 +1,80 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awscloudwatchlogsexporter"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func reasonAttr(reason string) attribute.KeyValue {
+	return attribute.String("reason", reason)
+}
+
+// batcherTelemetry records the metrics that let users tune QueueSize against
+// the batcher's actual behavior: awscwlogs_batch_size_bytes,
+// awscwlogs_batch_event_count, and awscwlogs_dropped_events_total.
+type batcherTelemetry struct {
+	batchSizeBytes  metric.Int64Histogram
+	batchEventCount metric.Int64Histogram
+	droppedEvents   metric.Int64Counter
+}
+
+func newBatcherTelemetry(meter metric.Meter) (*batcherTelemetry, error) {
+	batchSizeBytes, err := meter.Int64Histogram(
+		"awscwlogs_batch_size_bytes",
+		metric.WithDescription("Size in bytes of each batch sent via PutLogEvents"),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create awscwlogs_batch_size_bytes: %w", err)
+	}
+
+	batchEventCount, err := meter.Int64Histogram(
+		"awscwlogs_batch_event_count",
+		metric.WithDescription("Number of log events in each batch sent via PutLogEvents"),
+		metric.WithUnit("1"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create awscwlogs_batch_event_count: %w", err)
+	}
+
+	droppedEvents, err := meter.Int64Counter(
+		"awscwlogs_dropped_events_total",
+		metric.WithDescription("Number of log events dropped instead of sent to CloudWatch Logs"),
+		metric.WithUnit("1"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create awscwlogs_dropped_events_total: %w", err)
+	}
+
+	return &batcherTelemetry{
+		batchSizeBytes:  batchSizeBytes,
+		batchEventCount: batchEventCount,
+		droppedEvents:   droppedEvents,
+	}, nil
+}
+
+func (t *batcherTelemetry) recordBatch(batch []*cloudwatchlogs.InputLogEvent) {
+	t.batchSizeBytes.Record(context.Background(), int64(batchSizeBytes(batch)))
+	t.batchEventCount.Record(context.Background(), int64(len(batch)))
+}
+
+func (t *batcherTelemetry) recordDropped(dropped map[string]int) {
+	for reason, count := range dropped {
+		t.droppedEvents.Add(context.Background(), int64(count), metric.WithAttributes(reasonAttr(reason)))
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awscloudwatchlogsexporter"
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// nameTemplateData is the data made available to LogGroupName and
+// LogStreamName templates, e.g. {{ index .Resource "faas.name" }} or
+// {{ .Attributes.host_name }}.
+type nameTemplateData struct {
+	Resource   map[string]interface{}
+	Attributes map[string]interface{}
+}
+
+// nameTemplate resolves either a static destination name or, when the
+// configured name contains "{{", a per-log-record template.
+type nameTemplate struct {
+	raw      string
+	isStatic bool
+	tmpl     *template.Template
+}
+
+func parseNameTemplate(raw string) (*nameTemplate, error) {
+	if !strings.Contains(raw, "{{") {
+		return &nameTemplate{raw: raw, isStatic: true}, nil
+	}
+	// missingkey=error turns a reference to a resource/attribute that isn't
+	// present on a given record into an execution error instead of the
+	// literal string "<no value>". CloudWatch log group/stream names reject
+	// spaces and "<"/">", so silently resolving to "<no value>" would make
+	// ensureStream fail on every record missing that key; failing fast here
+	// lets the caller reject or fall back instead.
+	tmpl, err := template.New(raw).Option("missingkey=error").Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &nameTemplate{raw: raw, tmpl: tmpl}, nil
+}
+
+// resolve executes the template against resource/attributes, returning an
+// error if it references a key that isn't present on this record.
+func (n *nameTemplate) resolve(resource, attributes pcommon.Map) (string, error) {
+	if n.isStatic {
+		return n.raw, nil
+	}
+	data := nameTemplateData{
+		Resource:   resource.AsRaw(),
+		Attributes: attributes.AsRaw(),
+	}
+	var sb strings.Builder
+	if err := n.tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to resolve name template %q: %w", n.raw, err)
+	}
+	// index on a missing map key is not caught by missingkey=error and
+	// renders as "<no value>", so reject that result here as well.
+	if strings.Contains(sb.String(), "<no value>") {
+		return "", fmt.Errorf("failed to resolve name template %q: referenced key is not present", n.raw)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awscloudwatchlogsexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestParseNameTemplate_Static(t *testing.T) {
+	tmpl, err := parseNameTemplate("/aws/my-service")
+	require.NoError(t, err)
+
+	resource := pcommon.NewMap()
+	attrs := pcommon.NewMap()
+	name, err := tmpl.resolve(resource, attrs)
+	require.NoError(t, err)
+	assert.Equal(t, "/aws/my-service", name)
+}
+
+func TestParseNameTemplate_ResolvesResourceAndAttributes(t *testing.T) {
+	tmpl, err := parseNameTemplate(`/aws/lambda/{{ index .Resource "faas.name" }}/{{ .Attributes.host_name }}`)
+	require.NoError(t, err)
+
+	resource := pcommon.NewMap()
+	resource.PutStr("faas.name", "my-fn")
+	attrs := pcommon.NewMap()
+	attrs.PutStr("host_name", "host-1")
+
+	name, err := tmpl.resolve(resource, attrs)
+	require.NoError(t, err)
+	assert.Equal(t, "/aws/lambda/my-fn/host-1", name)
+}
+
+func TestParseNameTemplate_InvalidTemplate(t *testing.T) {
+	_, err := parseNameTemplate("{{ .Attributes.unterminated")
+	assert.Error(t, err)
+}
+
+func TestParseNameTemplate_MissingFieldFailsResolve(t *testing.T) {
+	// A missing resource/attribute must fail resolution rather than render
+	// the literal string "<no value>": CloudWatch log group/stream names
+	// reject spaces and "<"/">", so a silently-resolved "<no value>" would
+	// make every affected record fail to ever be delivered.
+	tmpl, err := parseNameTemplate(`{{ .Attributes.missing }}`)
+	require.NoError(t, err)
+
+	_, err = tmpl.resolve(pcommon.NewMap(), pcommon.NewMap())
+	assert.Error(t, err)
+}